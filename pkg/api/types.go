@@ -0,0 +1,154 @@
+package api
+
+import "time"
+
+// Config is the top level configuration for the UI, keyed by cluster name.
+type Config struct {
+	Clusters map[string]ClusterConfiguration `json:"clusters"`
+
+	// Concurrency bounds how many clusters are fetched in parallel. <= 0
+	// falls back to a provider-defined default.
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// FetchTimeoutSeconds bounds how long a single cluster's fetch may run
+	// before it is cancelled. <= 0 falls back to a provider-defined default.
+	FetchTimeoutSeconds int `json:"fetchTimeoutSeconds,omitempty"`
+}
+
+// ClusterConfiguration is the user supplied configuration for a single cluster.
+type ClusterConfiguration struct {
+	Kubeconfig string `json:"kubeconfig"`
+}
+
+// Cluster is the aggregated, point-in-time view of a single cluster that is
+// rendered on the dashboard.
+type Cluster struct {
+	Name       string     `json:"name"`
+	Kubernetes Kubernetes `json:"kubernetes"`
+	Platform   Platform   `json:"platform"`
+
+	// Alerts carries cluster-level findings that aren't tied to a single
+	// node or subsystem, e.g. a failed fetch on the last refresh.
+	Alerts []Alert `json:"alerts,omitempty"`
+}
+
+// FetchResult records the outcome of fetching a single cluster, so a
+// multi-cluster refresh can report partial failures and per-cluster
+// latency instead of dropping them or stalling on the slowest cluster.
+type FetchResult struct {
+	ClusterName string        `json:"clusterName"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+	Latency     time.Duration `json:"latency"`
+}
+
+// Recognised values for Platform.Name.
+const (
+	PlatformEKS     = "eks"
+	PlatformGKE     = "gke"
+	PlatformAKS     = "aks"
+	PlatformRKE     = "rke"
+	PlatformKubeadm = "kubeadm"
+	PlatformUnknown = "unknown"
+)
+
+// Platform identifies the managed Kubernetes offering (or lack thereof)
+// that a cluster is running on.
+type Platform struct {
+	// Name is one of "eks", "gke", "aks", "rke", "kubeadm" or "unknown".
+	Name string `json:"name"`
+
+	// Version is the platform-specific build identifier parsed out of the
+	// control plane's GitVersion suffix, e.g. the GKE patch ("1200") or the
+	// AKS build hash. Empty when it cannot be derived.
+	Version string `json:"version,omitempty"`
+
+	// Region is the cloud region the cluster runs in, derived from the
+	// topology.kubernetes.io/region node label. Empty when it cannot be
+	// derived, e.g. for kubeadm clusters.
+	Region string `json:"region,omitempty"`
+}
+
+// Alert is a single actionable finding surfaced to the dashboard. Level is
+// one of "warning" or "error".
+type Alert struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// DeprecatedAPIUsage records live usage of a deprecated or removed
+// Kubernetes API on a cluster.
+type DeprecatedAPIUsage struct {
+	// GroupVersionKind is the deprecated API, e.g. "networking.k8s.io/v1beta1 Ingress".
+	GroupVersionKind string `json:"groupVersionKind"`
+
+	DeprecatedIn string `json:"deprecatedIn"`
+	RemovedIn    string `json:"removedIn"`
+	Replacement  string `json:"replacement"`
+
+	Count       int      `json:"count"`
+	SampleNames []string `json:"sampleNames,omitempty"`
+}
+
+// Node is a single Kubernetes node and the component versions/alerts
+// discovered for it.
+type Node struct {
+	Name             string  `json:"name"`
+	InternalIP       string  `json:"internalIP"`
+	ExternalIP       string  `json:"externalIP"`
+	KernelVersion    string  `json:"kernelVersion"`
+	KubeletVersion   string  `json:"kubeletVersion"`
+	OSImage          string  `json:"osImage"`
+	ContainerRuntime string  `json:"containerRuntime"`
+	Unschedulable    bool    `json:"unschedulable"`
+	Alerts           []Alert `json:"alerts,omitempty"`
+}
+
+// ServerVersion is the control plane's reported version, as returned by
+// the Kubernetes discovery API.
+type ServerVersion struct {
+	GitVersion string `json:"gitVersion"`
+	Major      string `json:"major"`
+	Minor      string `json:"minor"`
+	GitCommit  string `json:"gitCommit"`
+}
+
+// Kubernetes is the aggregated view of a single cluster's nodes and
+// component versions.
+type Kubernetes struct {
+	CPU            int     `json:"cpu"`
+	Memory         int64   `json:"memory"`
+	Disk           int64   `json:"disk"`
+	KubeletVersion string  `json:"kubeletVersion"`
+	KernelVersion  string  `json:"kernelVersion"`
+	OSVersion      string  `json:"osVersion"`
+	CRIVersion     string  `json:"criVersion"`
+	KubeletAlerts  []Alert `json:"kubeletAlerts,omitempty"`
+	KernelAlerts   []Alert `json:"kernelAlerts,omitempty"`
+	OSAlerts       []Alert `json:"osAlerts,omitempty"`
+	CRIAlerts      []Alert `json:"criAlerts,omitempty"`
+
+	// ServerVersion is the control plane's version, used to detect
+	// kubelet/apiserver version skew and end-of-life control planes.
+	ServerVersion       ServerVersion `json:"serverVersion"`
+	ServerVersionAlerts []Alert       `json:"serverVersionAlerts,omitempty"`
+
+	// Benchmark is the CIS Kubernetes Benchmark version applicable to this
+	// cluster, and CISAlerts flags clusters running against a stale one.
+	Benchmark string  `json:"benchmark,omitempty"`
+	CISAlerts []Alert `json:"cisAlerts,omitempty"`
+
+	// DeprecatedAPIs lists the deprecated Kubernetes APIs that still have
+	// live resources on this cluster, plus the removed APIs this cluster's
+	// version no longer serves (their Count is zero), and DeprecatedAPIAlerts
+	// surfaces them as warnings/errors depending on proximity to removal.
+	DeprecatedAPIs      []DeprecatedAPIUsage `json:"deprecatedAPIs,omitempty"`
+	DeprecatedAPIAlerts []Alert              `json:"deprecatedAPIAlerts,omitempty"`
+
+	// NotReadyNodes and CordonedNodes are cluster-wide rollups of node
+	// health, so the dashboard can surface them without walking Nodes.
+	NotReadyNodes int `json:"notReadyNodes"`
+	CordonedNodes int `json:"cordonedNodes"`
+
+	Nodes []Node `json:"nodes"`
+}
@@ -0,0 +1,138 @@
+// Package benchmarks maps a cluster's Kubernetes version and detected
+// platform to the applicable CIS Kubernetes Benchmark version.
+package benchmarks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/flanksource/karina-ui/pkg/api"
+)
+
+// defaultBenchmark is returned when a Kubernetes version predates every
+// entry in the generic mapping table.
+const defaultBenchmark = "cis-1.6"
+
+// NewestKnownBenchmark is the newest generic CIS benchmark these tables
+// know about, used to warn operators running anything older.
+const NewestKnownBenchmark = "cis-1.8"
+
+// newestPlatformBenchmark is the newest benchmark published for each
+// managed platform's own table.
+var newestPlatformBenchmark = map[string]string{
+	api.PlatformEKS: "eks-1.4.0",
+	api.PlatformGKE: "gke-1.4.0",
+	api.PlatformAKS: "aks-1.4.0",
+}
+
+// defaultPlatformBenchmark is returned when a Kubernetes version predates
+// every entry in a managed platform's own table. Kept separate from
+// defaultBenchmark so a pre-table EKS/GKE/AKS cluster is still reported in
+// that platform's own benchmark namespace instead of the generic one.
+var defaultPlatformBenchmark = map[string]string{
+	api.PlatformEKS: "eks-1.3.0",
+	api.PlatformGKE: "gke-1.3.0",
+	api.PlatformAKS: "aks-1.3.0",
+}
+
+// NewestBenchmarkFor returns the newest benchmark known for the given
+// platform, falling back to the generic NewestKnownBenchmark.
+func NewestBenchmarkFor(platform string) string {
+	if newest, ok := newestPlatformBenchmark[platform]; ok {
+		return newest
+	}
+	return NewestKnownBenchmark
+}
+
+// genericBenchmarks maps "<major>.<minor>" Kubernetes versions to the
+// generic CIS Kubernetes Benchmark for clusters with no detected managed
+// platform (kubeadm, rke, unknown).
+var genericBenchmarks = map[string]string{
+	"1.24": "cis-1.7",
+	"1.25": "cis-1.7",
+	"1.26": "cis-1.8",
+	"1.27": "cis-1.8",
+	"1.28": "cis-1.8",
+}
+
+// platformBenchmarks maps api.Platform.Name to its own "<major>.<minor>" to
+// benchmark-version table, for the managed offerings that publish a
+// CIS benchmark tailored to their hardening defaults.
+var platformBenchmarks = map[string]map[string]string{
+	api.PlatformEKS: {
+		"1.24": "eks-1.3.0",
+		"1.25": "eks-1.4.0",
+		"1.26": "eks-1.4.0",
+		"1.27": "eks-1.4.0",
+		"1.28": "eks-1.4.0",
+	},
+	api.PlatformGKE: {
+		"1.24": "gke-1.3.0",
+		"1.25": "gke-1.4.0",
+		"1.26": "gke-1.4.0",
+		"1.27": "gke-1.4.0",
+		"1.28": "gke-1.4.0",
+	},
+	api.PlatformAKS: {
+		"1.24": "aks-1.3.0",
+		"1.25": "aks-1.4.0",
+		"1.26": "aks-1.4.0",
+		"1.27": "aks-1.4.0",
+		"1.28": "aks-1.4.0",
+	},
+}
+
+// BenchmarkFor returns the CIS Kubernetes Benchmark applicable to a cluster
+// running Kubernetes version kv (e.g. "v1.27.3") on the named platform,
+// using the platform-specific table when one exists and falling back to
+// the generic table otherwise.
+func BenchmarkFor(platform, kv string) (string, error) {
+	if table, ok := platformBenchmarks[platform]; ok {
+		return mapToBenchmarkVersion(table, kv, defaultPlatformBenchmark[platform])
+	}
+	return mapToBenchmarkVersion(genericBenchmarks, kv, defaultBenchmark)
+}
+
+// mapToBenchmarkVersion looks up kv's "<major>.<minor>" in kubeToBenchmarkMap;
+// if there's no exact match it decrements the minor version (1.27 -> 1.26 ->
+// ...) until one is found, falling back to fallback once the minor version
+// is exhausted. fallback must be in the same benchmark namespace as
+// kubeToBenchmarkMap's values so callers never mix a platform table with the
+// generic fallback.
+func mapToBenchmarkVersion(kubeToBenchmarkMap map[string]string, kv string, fallback string) (string, error) {
+	major, minor, err := majorMinor(kv)
+	if err != nil {
+		return "", err
+	}
+
+	for m := minor; m >= 0; m-- {
+		if benchmark, ok := kubeToBenchmarkMap[fmt.Sprintf("%d.%d", major, m)]; ok {
+			return benchmark, nil
+		}
+	}
+
+	return fallback, nil
+}
+
+// majorMinor parses the major and minor components out of a Kubernetes
+// GitVersion-style string such as "v1.27.3".
+func majorMinor(kv string) (int, int, error) {
+	trimmed := strings.TrimPrefix(kv, "v")
+	parts := strings.SplitN(trimmed, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("could not parse kubernetes version %q", kv)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse major version from %q: %w", kv, err)
+	}
+
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse minor version from %q: %w", kv, err)
+	}
+
+	return major, minor, nil
+}
@@ -0,0 +1,93 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"github.com/flanksource/karina-ui/pkg/api"
+)
+
+func TestBenchmarkFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		kv       string
+		want     string
+		wantErr  bool
+	}{
+		{name: "generic exact match", platform: api.PlatformKubeadm, kv: "v1.27.3", want: "cis-1.8"},
+		{name: "generic decrements to match", platform: api.PlatformUnknown, kv: "v1.29.0", want: "cis-1.8"},
+		{name: "generic falls back to default below every entry", platform: api.PlatformKubeadm, kv: "v1.20.0", want: defaultBenchmark},
+		{name: "eks exact match", platform: api.PlatformEKS, kv: "v1.26.5-eks-1234567", want: "eks-1.4.0"},
+		{name: "eks decrements to match", platform: api.PlatformEKS, kv: "v1.29.0-eks-1234567", want: "eks-1.4.0"},
+		{name: "eks falls back to its own default, not the generic one", platform: api.PlatformEKS, kv: "v1.20.0-eks-1234567", want: "eks-1.3.0"},
+		{name: "gke falls back to its own default, not the generic one", platform: api.PlatformGKE, kv: "v1.20.0", want: "gke-1.3.0"},
+		{name: "aks falls back to its own default, not the generic one", platform: api.PlatformAKS, kv: "v1.20.0", want: "aks-1.3.0"},
+		{name: "unparseable version errors", platform: api.PlatformKubeadm, kv: "not-a-version", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BenchmarkFor(tt.platform, tt.kv)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BenchmarkFor(%q, %q) expected an error, got benchmark %q", tt.platform, tt.kv, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("BenchmarkFor(%q, %q) returned unexpected error: %v", tt.platform, tt.kv, err)
+			}
+			if got != tt.want {
+				t.Errorf("BenchmarkFor(%q, %q) = %q, want %q", tt.platform, tt.kv, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapToBenchmarkVersionNeverMixesNamespaces(t *testing.T) {
+	table := map[string]string{"1.27": "eks-1.4.0"}
+
+	got, err := mapToBenchmarkVersion(table, "v1.20.0", "eks-1.3.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "eks-1.3.0" {
+		t.Errorf("mapToBenchmarkVersion fell back to %q, want the supplied fallback %q", got, "eks-1.3.0")
+	}
+}
+
+func TestMajorMinor(t *testing.T) {
+	tests := []struct {
+		kv        string
+		wantMajor int
+		wantMinor int
+		wantErr   bool
+	}{
+		{kv: "v1.27.3", wantMajor: 1, wantMinor: 27},
+		{kv: "1.27.3", wantMajor: 1, wantMinor: 27},
+		{kv: "v1.26.5-eks-1234567", wantMajor: 1, wantMinor: 26},
+		{kv: "v1.29.0-gke.1200", wantMajor: 1, wantMinor: 29},
+		{kv: "v1", wantErr: true},
+		{kv: "not-a-version", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kv, func(t *testing.T) {
+			major, minor, err := majorMinor(tt.kv)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("majorMinor(%q) expected an error, got %d.%d", tt.kv, major, minor)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("majorMinor(%q) returned unexpected error: %v", tt.kv, err)
+			}
+			if major != tt.wantMajor || minor != tt.wantMinor {
+				t.Errorf("majorMinor(%q) = %d.%d, want %d.%d", tt.kv, major, minor, tt.wantMajor, tt.wantMinor)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/karina-ui/pkg/api"
+	"github.com/flanksource/kommons"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxSampleResourceNames caps how many resource names are recorded per
+// deprecated API so the dashboard payload doesn't balloon on large clusters.
+const maxSampleResourceNames = 5
+
+// deprecatedAPI describes a single Kubernetes API that has been deprecated
+// or removed, and what replaced it. Borrowed from the deprecated-API
+// migration tables Helm ships with `helm template --validate`.
+type deprecatedAPI struct {
+	GVR          schema.GroupVersionResource
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	Replacement  string
+}
+
+// deprecatedAPITable is the set of deprecated/removed APIs this scanner
+// checks for. Versions are the Kubernetes release each API was deprecated
+// or removed in, per the upstream deprecation guide.
+var deprecatedAPITable = []deprecatedAPI{
+	{
+		GVR:          schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses"},
+		Kind:         "Ingress",
+		DeprecatedIn: "v1.14.0",
+		RemovedIn:    "v1.22.0",
+		Replacement:  "networking.k8s.io/v1 Ingress",
+	},
+	{
+		GVR:          schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "ingresses"},
+		Kind:         "Ingress",
+		DeprecatedIn: "v1.14.0",
+		RemovedIn:    "v1.22.0",
+		Replacement:  "networking.k8s.io/v1 Ingress",
+	},
+	{
+		GVR:          schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: "podsecuritypolicies"},
+		Kind:         "PodSecurityPolicy",
+		DeprecatedIn: "v1.21.0",
+		RemovedIn:    "v1.25.0",
+		Replacement:  "Pod Security Admission",
+	},
+	{
+		GVR:          schema.GroupVersionResource{Group: "batch", Version: "v1beta1", Resource: "cronjobs"},
+		Kind:         "CronJob",
+		DeprecatedIn: "v1.21.0",
+		RemovedIn:    "v1.25.0",
+		Replacement:  "batch/v1 CronJob",
+	},
+	{
+		GVR:          schema.GroupVersionResource{Group: "autoscaling", Version: "v2beta1", Resource: "horizontalpodautoscalers"},
+		Kind:         "HorizontalPodAutoscaler",
+		DeprecatedIn: "v1.19.0",
+		RemovedIn:    "v1.25.0",
+		Replacement:  "autoscaling/v2 HorizontalPodAutoscaler",
+	},
+	{
+		GVR:          schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1beta1", Resource: "customresourcedefinitions"},
+		Kind:         "CustomResourceDefinition",
+		DeprecatedIn: "v1.16.0",
+		RemovedIn:    "v1.22.0",
+		Replacement:  "apiextensions.k8s.io/v1 CustomResourceDefinition",
+	},
+}
+
+// scanDeprecatedAPIs lists every resource in deprecatedAPITable via the
+// dynamic client and records which ones still have live resources on the
+// cluster. An API that is no longer served because the cluster is at or past
+// its RemovedIn is recorded too, with a Count of zero and no sample names.
+// clusterVersion is the control plane's GitVersion, used to tell a
+// GroupVersion that is genuinely gone (cluster is at or past RemovedIn) from
+// one that 404s for some other reason.
+func scanDeprecatedAPIs(ctx context.Context, client *kommons.Client, clusterVersion string) ([]api.DeprecatedAPIUsage, error) {
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get dynamic client")
+	}
+
+	sv, err := semver.NewVersion(clusterVersion)
+	if err != nil {
+		logger.Errorf("could not parse cluster version %s: %v", clusterVersion, err)
+	}
+
+	usages := []api.DeprecatedAPIUsage{}
+
+	for _, dep := range deprecatedAPITable {
+		list, err := dynamicClient.Resource(dep.GVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "failed to list %s/%s %s", dep.GVR.Group, dep.GVR.Version, dep.Kind)
+			}
+
+			if sv != nil && isRemoved(sv, dep.RemovedIn) {
+				// The GroupVersion is genuinely gone because the cluster has
+				// passed RemovedIn. We can no longer enumerate which
+				// resources used it, but that's exactly the case operators
+				// most need to hear about, so still record it.
+				usages = append(usages, api.DeprecatedAPIUsage{
+					GroupVersionKind: fmt.Sprintf("%s/%s %s", dep.GVR.Group, dep.GVR.Version, dep.Kind),
+					DeprecatedIn:     dep.DeprecatedIn,
+					RemovedIn:        dep.RemovedIn,
+					Replacement:      dep.Replacement,
+				})
+				continue
+			}
+
+			// Not served for some other reason, e.g. disabled via
+			// --runtime-config - nothing to report.
+			continue
+		}
+
+		if len(list.Items) == 0 {
+			continue
+		}
+
+		sampleNames := []string{}
+		for i, item := range list.Items {
+			if i >= maxSampleResourceNames {
+				break
+			}
+			sampleNames = append(sampleNames, item.GetName())
+		}
+
+		usages = append(usages, api.DeprecatedAPIUsage{
+			GroupVersionKind: fmt.Sprintf("%s/%s %s", dep.GVR.Group, dep.GVR.Version, dep.Kind),
+			DeprecatedIn:     dep.DeprecatedIn,
+			RemovedIn:        dep.RemovedIn,
+			Replacement:      dep.Replacement,
+			Count:            len(list.Items),
+			SampleNames:      sampleNames,
+		})
+	}
+
+	return usages, nil
+}
+
+// isRemoved reports whether sv is at or past the release a deprecated API
+// was removed in.
+func isRemoved(sv *semver.Version, removedIn string) bool {
+	removedInSv, err := semver.NewVersion(removedIn)
+	if err != nil {
+		return false
+	}
+	return !sv.LessThan(removedInSv)
+}
+
+// deprecatedAPIAlerts turns each DeprecatedAPIUsage into a warning, or an
+// error once the cluster version is at or past the API's removal.
+func deprecatedAPIAlerts(usages []api.DeprecatedAPIUsage, clusterVersion string) []api.Alert {
+	alerts := []api.Alert{}
+
+	sv, err := semver.NewVersion(clusterVersion)
+	if err != nil {
+		logger.Errorf("could not parse cluster version %s: %v", clusterVersion, err)
+		return alerts
+	}
+
+	for _, usage := range usages {
+		level := "warning"
+		removed := isRemoved(sv, usage.RemovedIn)
+		if removed {
+			level = "error"
+		}
+
+		message := fmt.Sprintf("%s removed in %s - %d resources still using it, replace with %s", usage.GroupVersionKind, usage.RemovedIn, usage.Count, usage.Replacement)
+		if removed && usage.Count == 0 {
+			// scanDeprecatedAPIs couldn't enumerate resources once the
+			// GroupVersion stopped being served - say so rather than
+			// claiming a count of zero.
+			message = fmt.Sprintf("%s removed in %s - API is no longer served, historical usage could not be confirmed, replace with %s", usage.GroupVersionKind, usage.RemovedIn, usage.Replacement)
+		}
+
+		alerts = append(alerts, api.Alert{Level: level, Message: message})
+	}
+
+	return alerts
+}
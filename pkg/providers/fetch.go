@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/flanksource/karina-ui/pkg/api"
+)
+
+// DefaultFetchConcurrency bounds how many clusters are fetched in parallel
+// when api.Config.Concurrency is unset.
+const DefaultFetchConcurrency = 4
+
+// DefaultFetchTimeout bounds how long a single cluster's Fetch may run when
+// api.Config.FetchTimeoutSeconds is unset.
+const DefaultFetchTimeout = 30 * time.Second
+
+// fetchDurations exposes the latency of each cluster's most recent fetch,
+// keyed by cluster name, so operators can see which clusters are slow.
+var fetchDurations = expvar.NewMap("sysmonitor_cluster_fetch_duration_ms")
+
+// FetchAll refreshes every cluster in clusters that has an entry in
+// config.Clusters through provider (clusters without one are skipped and get
+// no FetchResult), running up
+// to config.Concurrency fetches at a time and bounding each one with a
+// context.WithTimeout derived from config.FetchTimeoutSeconds. A cluster
+// whose fetch fails is not dropped: its FetchResult reports the failure and
+// the failure is also recorded as an api.Alert on the cluster itself, so a
+// slow or unreachable cluster can no longer stall the rest of the refresh.
+func FetchAll(provider Provider, config api.Config, clusters map[string]*api.Cluster) []api.FetchResult {
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+
+	timeout := DefaultFetchTimeout
+	if config.FetchTimeoutSeconds > 0 {
+		timeout = time.Duration(config.FetchTimeoutSeconds) * time.Second
+	}
+
+	sem := make(chan struct{}, concurrency)
+	results := make([]api.FetchResult, 0, len(clusters))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for name, clusterConfig := range config.Clusters {
+		cluster, found := clusters[name]
+		if !found {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(name string, clusterConfig api.ClusterConfiguration, cluster *api.Cluster) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			result := provider.Fetch(ctx, cluster, clusterConfig)
+			fetchDurations.Set(name, expvarMs(result.Latency))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// Reset rather than append: cluster.Alerts only reflects this
+			// cycle's fetch, so a recovered cluster doesn't keep showing a
+			// stale failure and a cluster failing every cycle doesn't
+			// accumulate duplicate alerts forever.
+			if result.Success {
+				cluster.Alerts = nil
+			} else {
+				cluster.Alerts = []api.Alert{{
+					Level:   "error",
+					Message: "failed to fetch cluster: " + result.Error,
+				}}
+			}
+			results = append(results, result)
+		}(name, clusterConfig, cluster)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// expvarMs wraps a duration as an expvar.Var reporting milliseconds.
+func expvarMs(d time.Duration) expvar.Var {
+	ms := new(expvar.Float)
+	ms.Set(float64(d.Milliseconds()))
+	return ms
+}
@@ -5,16 +5,28 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/karina-ui/pkg/api"
+	"github.com/flanksource/karina-ui/pkg/benchmarks"
 	"github.com/flanksource/kommons"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// minSupportedKubernetesVersion is the oldest control plane release still
+// within the Kubernetes project's support window. Clusters running an
+// apiserver older than this are flagged as end-of-life.
+const minSupportedKubernetesVersion = "v1.23.0"
+
+// maxSupportedKubeletSkew is the number of minor versions a kubelet is
+// allowed to lag behind the kube-apiserver, per the upstream version skew
+// policy.
+const maxSupportedKubeletSkew = 2
+
 type Kubernetes struct {
 	clients map[string]*kommons.Client
 }
@@ -42,7 +54,21 @@ func NewKubernetes(config api.Config) (Provider, error) {
 	return k8s, nil
 }
 
-func (k *Kubernetes) Fetch(cluster *api.Cluster, config api.ClusterConfiguration) error {
+// Fetch refreshes cluster's snapshot and reports the outcome as an
+// api.FetchResult. The actual work happens in fetch; this wrapper only
+// exists to time it and translate a returned error into the result shape
+// the Provider interface promises.
+func (k *Kubernetes) Fetch(ctx context.Context, cluster *api.Cluster, config api.ClusterConfiguration) api.FetchResult {
+	start := time.Now()
+
+	if err := k.fetch(ctx, cluster, config); err != nil {
+		return api.FetchResult{ClusterName: cluster.Name, Success: false, Error: err.Error(), Latency: time.Since(start)}
+	}
+
+	return api.FetchResult{ClusterName: cluster.Name, Success: true, Latency: time.Since(start)}
+}
+
+func (k *Kubernetes) fetch(ctx context.Context, cluster *api.Cluster, config api.ClusterConfiguration) error {
 	client, found := k.clients[cluster.Name]
 	if !found {
 		return errors.Errorf("kubernetes client for cluster %s not found", cluster.Name)
@@ -53,12 +79,37 @@ func (k *Kubernetes) Fetch(cluster *api.Cluster, config api.ClusterConfiguration
 		return errors.Errorf("failed to get clientset for cluster %s", cluster.Name)
 	}
 
-	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return errors.Errorf("failed to list nodes")
 	}
 
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return errors.Errorf("failed to list pods")
+	}
+
+	podCountByNode := map[string]int{}
+	for _, pod := range pods.Items {
+		podCountByNode[pod.Spec.NodeName]++
+	}
+
+	versionInfo, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get server version for cluster %s", cluster.Name)
+	}
+
+	serverVersion := api.ServerVersion{
+		GitVersion: versionInfo.GitVersion,
+		Major:      versionInfo.Major,
+		Minor:      versionInfo.Minor,
+		GitCommit:  versionInfo.GitCommit,
+	}
+
 	var cpus, memory, diskSize int64
+	var notReadyNodes, cordonedNodes int
 	var nodesList = []api.Node{}
 
 	for _, node := range nodes.Items {
@@ -66,7 +117,17 @@ func (k *Kubernetes) Fetch(cluster *api.Cluster, config api.ClusterConfiguration
 		memory += node.Status.Capacity.Memory().Value()
 		diskSize += node.Status.Capacity.StorageEphemeral().Value()
 
-		nodesList = append(nodesList, getNodeInfo(node))
+		n := getNodeInfo(node)
+		n.Alerts = append(n.Alerts, nodeConditionAlerts(node)...)
+		n.Alerts = append(n.Alerts, nodeResourcePressureAlerts(node, podCountByNode[node.Name])...)
+		nodesList = append(nodesList, n)
+
+		if !isNodeReady(node) {
+			notReadyNodes++
+		}
+		if node.Spec.Unschedulable {
+			cordonedNodes++
+		}
 	}
 
 	knv, kernelAlerts := addAlerts(nodesList, "Kernel", kernelVersion)
@@ -74,19 +135,39 @@ func (k *Kubernetes) Fetch(cluster *api.Cluster, config api.ClusterConfiguration
 	crv, criAlerts := addAlerts(nodesList, "CRI", criVersion)
 	osv, osAlerts := addAlerts(nodesList, "OS", osVersion)
 
+	serverVersionAlerts := addServerVersionAlerts(nodesList, serverVersion)
+
+	cluster.Platform = detectPlatform(serverVersion.GitVersion, nodes.Items)
+
+	benchmark, cisAlerts := addCISAlerts(cluster.Platform.Name, serverVersion.GitVersion)
+
+	deprecatedAPIs, err := scanDeprecatedAPIs(ctx, client, serverVersion.GitVersion)
+	if err != nil {
+		return errors.Wrapf(err, "failed to scan deprecated APIs for cluster %s", cluster.Name)
+	}
+	depAPIAlerts := deprecatedAPIAlerts(deprecatedAPIs, serverVersion.GitVersion)
+
 	cluster.Kubernetes = api.Kubernetes{
-		CPU:            int(cpus),
-		Memory:         memory,
-		Disk:           diskSize,
-		KubeletVersion: kbv,
-		KernelVersion:  knv,
-		OSVersion:      osv,
-		CRIVersion:     crv,
-		KubeletAlerts:  kubeletAlerts,
-		KernelAlerts:   kernelAlerts,
-		OSAlerts:       osAlerts,
-		CRIAlerts:      criAlerts,
-		Nodes:          nodesList,
+		CPU:                 int(cpus),
+		Memory:              memory,
+		Disk:                diskSize,
+		KubeletVersion:      kbv,
+		KernelVersion:       knv,
+		OSVersion:           osv,
+		CRIVersion:          crv,
+		KubeletAlerts:       kubeletAlerts,
+		KernelAlerts:        kernelAlerts,
+		OSAlerts:            osAlerts,
+		CRIAlerts:           criAlerts,
+		ServerVersion:       serverVersion,
+		ServerVersionAlerts: serverVersionAlerts,
+		Benchmark:           benchmark,
+		CISAlerts:           cisAlerts,
+		DeprecatedAPIs:      deprecatedAPIs,
+		DeprecatedAPIAlerts: depAPIAlerts,
+		NotReadyNodes:       notReadyNodes,
+		CordonedNodes:       cordonedNodes,
+		Nodes:               nodesList,
 	}
 
 	return nil
@@ -116,6 +197,7 @@ func getNodeInfo(node v1.Node) api.Node {
 		KubeletVersion:   node.Status.NodeInfo.KubeletVersion,
 		OSImage:          node.Status.NodeInfo.OSImage,
 		ContainerRuntime: node.Status.NodeInfo.ContainerRuntimeVersion,
+		Unschedulable:    node.Spec.Unschedulable,
 	}
 
 	return n
@@ -168,6 +250,69 @@ func addAlerts(nodes []api.Node, component string, fn versionGetterFn) (string,
 	return latestVersion, alerts
 }
 
+// addServerVersionAlerts compares the control plane version against each
+// node's kubelet version and against the oldest Kubernetes release still
+// supported upstream, matching the documented version skew policy:
+// https://kubernetes.io/releases/version-skew-policy/
+func addServerVersionAlerts(nodes []api.Node, serverVersion api.ServerVersion) []api.Alert {
+	alerts := []api.Alert{}
+
+	sv, err := semver.NewVersion(serverVersion.GitVersion)
+	if err != nil {
+		logger.Errorf("could not parse server version %s: %v", serverVersion.GitVersion, err)
+		return alerts
+	}
+
+	minSupported, _ := semver.NewVersion(minSupportedKubernetesVersion)
+	if sv.LessThan(minSupported) {
+		alerts = append(alerts, api.Alert{
+			Level:   "error",
+			Message: fmt.Sprintf("control plane version %s is older than the oldest supported release %s", serverVersion.GitVersion, minSupportedKubernetesVersion),
+		})
+	}
+
+	for _, node := range nodes {
+		kv := kubeletVersion(node)
+		kubeletSv, err := semver.NewVersion(kv)
+		if err != nil {
+			logger.Errorf("could not parse kubelet version %s: %v", kv, err)
+			continue
+		}
+
+		skew := int(sv.Minor()) - int(kubeletSv.Minor())
+		if skew > maxSupportedKubeletSkew {
+			alerts = append(alerts, api.Alert{
+				Level:   "warning",
+				Message: fmt.Sprintf("node %s kubelet %s is %d minor versions behind control plane %s, exceeding the supported skew of %d", node.Name, kv, skew, serverVersion.GitVersion, maxSupportedKubeletSkew),
+			})
+		}
+	}
+
+	return alerts
+}
+
+// addCISAlerts maps the cluster's Kubernetes version and platform to the
+// applicable CIS Kubernetes Benchmark and warns when it's older than the
+// newest benchmark these tables know about.
+func addCISAlerts(platform, gitVersion string) (string, []api.Alert) {
+	alerts := []api.Alert{}
+
+	benchmark, err := benchmarks.BenchmarkFor(platform, gitVersion)
+	if err != nil {
+		logger.Errorf("could not map %s to a CIS benchmark: %v", gitVersion, err)
+		return "", alerts
+	}
+
+	if newest := benchmarks.NewestBenchmarkFor(platform); benchmark != newest {
+		alerts = append(alerts, api.Alert{
+			Level:   "warning",
+			Message: fmt.Sprintf("cluster is on CIS %s, current is %s", benchmark, newest),
+		})
+	}
+
+	return benchmark, alerts
+}
+
 func criVersion(node api.Node) string {
 	version := node.ContainerRuntime
 	if strings.HasPrefix(version, "containerd://") {
@@ -0,0 +1,94 @@
+package providers
+
+import (
+	"fmt"
+
+	"github.com/flanksource/karina-ui/pkg/api"
+	v1 "k8s.io/api/core/v1"
+)
+
+// minAllocatableCPURatio is the minimum fraction of a node's CPU capacity
+// that should remain allocatable; below this, system-reserved resources
+// are eating too much of the node.
+const minAllocatableCPURatio = 0.85
+
+// minPodHeadroomRatio is the minimum fraction of a node's allocatable pod
+// slots that should be free.
+const minPodHeadroomRatio = 0.10
+
+// nodeConditionAlerts translates a node's status conditions and
+// schedulability into alerts: not-ready, the various pressure conditions,
+// network unavailability, and cordoning.
+func nodeConditionAlerts(node v1.Node) []api.Alert {
+	alerts := []api.Alert{}
+
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case v1.NodeReady:
+			if condition.Status != v1.ConditionTrue {
+				alerts = append(alerts, api.Alert{
+					Level:   "error",
+					Message: fmt.Sprintf("node %s is not ready: %s", node.Name, condition.Message),
+				})
+			}
+		case v1.NodeMemoryPressure, v1.NodeDiskPressure, v1.NodePIDPressure, v1.NodeNetworkUnavailable:
+			if condition.Status == v1.ConditionTrue {
+				alerts = append(alerts, api.Alert{
+					Level:   "warning",
+					Message: fmt.Sprintf("node %s has condition %s: %s", node.Name, condition.Type, condition.Message),
+				})
+			}
+		}
+	}
+
+	if node.Spec.Unschedulable {
+		alerts = append(alerts, api.Alert{
+			Level:   "warning",
+			Message: fmt.Sprintf("node %s is cordoned", node.Name),
+		})
+	}
+
+	return alerts
+}
+
+// isNodeReady reports whether a node's Ready condition is True.
+func isNodeReady(node v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeResourcePressureAlerts warns when a node's allocatable resources
+// have been eaten into by system-reserved overhead (CPU) or by the
+// currently scheduled pods (pod count headroom).
+func nodeResourcePressureAlerts(node v1.Node, podCount int) []api.Alert {
+	alerts := []api.Alert{}
+
+	capacityCPU := node.Status.Capacity.Cpu().MilliValue()
+	allocatableCPU := node.Status.Allocatable.Cpu().MilliValue()
+	if capacityCPU > 0 {
+		ratio := float64(allocatableCPU) / float64(capacityCPU)
+		if ratio < minAllocatableCPURatio {
+			alerts = append(alerts, api.Alert{
+				Level:   "warning",
+				Message: fmt.Sprintf("node %s allocatable CPU is %.0f%% of capacity, below the %.0f%% threshold", node.Name, ratio*100, minAllocatableCPURatio*100),
+			})
+		}
+	}
+
+	allocatablePods := node.Status.Allocatable.Pods().Value()
+	if allocatablePods > 0 {
+		headroom := float64(allocatablePods-int64(podCount)) / float64(allocatablePods)
+		if headroom < minPodHeadroomRatio {
+			alerts = append(alerts, api.Alert{
+				Level:   "warning",
+				Message: fmt.Sprintf("node %s is running %d/%d pods, below %.0f%% headroom", node.Name, podCount, allocatablePods, minPodHeadroomRatio*100),
+			})
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/flanksource/karina-ui/pkg/api"
+	v1 "k8s.io/api/core/v1"
+)
+
+const regionLabel = "topology.kubernetes.io/region"
+
+var (
+	eksGitVersionRe = regexp.MustCompile(`-eks-([^\s]+)`)
+	gkeGitVersionRe = regexp.MustCompile(`-gke\.(\d+)`)
+	aksGitVersionRe = regexp.MustCompile(`\+aks-([^\s]+)`)
+)
+
+// detectPlatform classifies the managed Kubernetes platform a cluster runs
+// on, combining the control plane's GitVersion suffix, node ProviderIDs and
+// well-known node labels. Borrowed from the platform-detection technique
+// used by kube-bench.
+func detectPlatform(gitVersion string, nodes []v1.Node) api.Platform {
+	if match := eksGitVersionRe.FindStringSubmatch(gitVersion); match != nil {
+		return api.Platform{Name: api.PlatformEKS, Version: match[1], Region: regionFromNodes(nodes)}
+	}
+	if match := gkeGitVersionRe.FindStringSubmatch(gitVersion); match != nil {
+		return api.Platform{Name: api.PlatformGKE, Version: match[1], Region: regionFromNodes(nodes)}
+	}
+	if match := aksGitVersionRe.FindStringSubmatch(gitVersion); match != nil {
+		return api.Platform{Name: api.PlatformAKS, Version: match[1], Region: regionFromNodes(nodes)}
+	}
+
+	for _, node := range nodes {
+		switch {
+		case strings.HasPrefix(node.Spec.ProviderID, "aws://"):
+			return api.Platform{Name: api.PlatformEKS, Region: regionFromNodes(nodes)}
+		case strings.HasPrefix(node.Spec.ProviderID, "gce://"):
+			return api.Platform{Name: api.PlatformGKE, Region: regionFromNodes(nodes)}
+		case strings.HasPrefix(node.Spec.ProviderID, "azure://"):
+			return api.Platform{Name: api.PlatformAKS, Region: regionFromNodes(nodes)}
+		}
+
+		for label := range node.Labels {
+			switch {
+			case strings.HasPrefix(label, "eks.amazonaws.com/"):
+				return api.Platform{Name: api.PlatformEKS, Region: regionFromNodes(nodes)}
+			case strings.HasPrefix(label, "cloud.google.com/gke-"):
+				return api.Platform{Name: api.PlatformGKE, Region: regionFromNodes(nodes)}
+			case strings.HasPrefix(label, "kubernetes.azure.com/"):
+				return api.Platform{Name: api.PlatformAKS, Region: regionFromNodes(nodes)}
+			case strings.HasPrefix(label, "rke.cattle.io/"):
+				return api.Platform{Name: api.PlatformRKE, Region: regionFromNodes(nodes)}
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if _, ok := node.Labels["node-role.kubernetes.io/control-plane"]; ok {
+			return api.Platform{Name: api.PlatformKubeadm, Region: regionFromNodes(nodes)}
+		}
+		if _, ok := node.Labels["node-role.kubernetes.io/master"]; ok {
+			return api.Platform{Name: api.PlatformKubeadm, Region: regionFromNodes(nodes)}
+		}
+	}
+
+	return api.Platform{Name: api.PlatformUnknown}
+}
+
+func regionFromNodes(nodes []v1.Node) string {
+	for _, node := range nodes {
+		if region, ok := node.Labels[regionLabel]; ok {
+			return region
+		}
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package providers
+
+import (
+	"context"
+
+	"github.com/flanksource/karina-ui/pkg/api"
+)
+
+// Provider fetches a point-in-time snapshot of a single cluster and
+// populates it onto the given api.Cluster. Implementations must respect
+// ctx's deadline so a slow or unreachable cluster cannot stall a
+// multi-cluster refresh, and report the outcome as an api.FetchResult
+// rather than an opaque error so callers can record per-cluster
+// success/failure/latency.
+type Provider interface {
+	Fetch(ctx context.Context, cluster *api.Cluster, config api.ClusterConfiguration) api.FetchResult
+	Name() string
+}